@@ -2,141 +2,160 @@ package main
 
 import (
 	"bufio"
-	"encoding/csv"
 	"fmt"
-	"io"
-	"math"
-	"os"
-	"strconv"
-	"strings"
+
+	"github.com/spf13/afero"
+	"github.com/tanakachitsamba/labeltransform/config"
+	"github.com/tanakachitsamba/labeltransform/pipeline"
+	"golang.org/x/net/html/charset"
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/transform"
 )
 
 /*───────────────────────────────
    PRODUCTION  CODE
 ────────────────────────────────*/
 
-// StringToBinary converts the tokens `"true"` and `"false"` (case‑insensitive,
-// leading/trailing whitespace ignored) into `"1"` and `"0"` respectively.
-// Any other value returns an error.  The implementation works directly on
-// ASCII bytes, avoiding an allocation for strings.ToLower.
-func StringToBinary(val string) (string, error) {
-	s := strings.TrimSpace(strings.ToLower(val))
-
-	binMap := map[string]string{
-		"true":     "1",
-		"positive": "1",
-		"yes":      "1",
-		"1":        "1",
-		"false":    "0",
-		"negative": "0",
-		"no":       "0",
-		"0":        "0",
-	}
-	if b, ok := binMap[s]; ok {
-		return b, nil
-	}
-	return "", fmt.Errorf("unexpected label value: %q", val)
+// TransformOptions configures the optional behaviour of the
+// TransformLabelCSVFSWithOptions variant. The zero value auto-detects the
+// input's character encoding.
+type TransformOptions struct {
+	// Encoding forces the input's character encoding instead of
+	// auto-detecting it via BOM sniffing and charset.DetermineEncoding.
+	// Output is always written as UTF-8 regardless of this setting.
+	Encoding encoding.Encoding
+
+	// Workers caps how many rows are transformed concurrently. Zero (the
+	// default) uses runtime.GOMAXPROCS(0); row order in the output is
+	// unaffected by this setting.
+	Workers int
+
+	// Compression forces the codec used for both the input and the output
+	// file instead of detecting it from each file's name (and, for input,
+	// its magic bytes). CompressionAuto, the default, detects per file, so
+	// a .csv.gz input can be repacked as a .csv.zst output with no option
+	// set at all.
+	Compression Compression
+
+	// Config declares the column transforms to run, in order. A nil Config
+	// falls back to the original hard-coded behaviour: binarizing "label"
+	// and log1p-ing "true_duration_seconds" (the latter skipped if absent).
+	// A non-nil Config replaces that default entirely and is validated
+	// against the input's header before the pipeline runs.
+	Config *config.Config
+}
+
+// defaultColumnOps is the transform list used when TransformOptions.Config
+// is nil, preserving TransformLabelCSV's original one-dataset behaviour.
+var defaultColumnOps = []pipeline.Transformer{
+	pipeline.LabelBinarizer{Column: "label"},
+	pipeline.Log1pColumn{Column: "true_duration_seconds"},
 }
 
-// log1p is a helper because math.Log1p returns NaN for negative numbers.
-func log1p(x float64) float64 {
-	if x < 0 {
-		return 0
+// sniffLen is the number of leading bytes inspected when auto-detecting the
+// input's character encoding.
+const sniffLen = 4 << 10 // 4 KiB
+
+// inputDecoder picks the transform.Transformer used to decode the input
+// stream into UTF-8. If opts.Encoding is set it is used verbatim; otherwise
+// the first sniffLen bytes of br are peeked and fed to charset.DetermineEncoding,
+// with unicode.BOMOverride taking precedence for an explicit byte-order mark.
+func inputDecoder(br *bufio.Reader, opts TransformOptions) transform.Transformer {
+	if opts.Encoding != nil {
+		return opts.Encoding.NewDecoder()
 	}
-	return math.Log1p(x)
+	peek, _ := br.Peek(sniffLen)
+	enc, _, _ := charset.DetermineEncoding(peek, "")
+	return unicode.BOMOverride(enc.NewDecoder())
 }
 
-// TransformLabelCSV streams an input CSV file, rewrites the `label` column
-// using StringToBinary, and writes the result to outputFile.
+// TransformLabelCSV streams an input CSV file, applies the configured column
+// transforms, and writes the result to outputFile.
 // It is optimised for large files: constant memory overhead and buffered I/O.
+// It operates on the real OS filesystem; see TransformLabelCSVFS for a
+// variant that can run against any afero.Fs.
 func TransformLabelCSV(inputFile, outputFile string) error {
+	return TransformLabelCSVFS(afero.NewOsFs(), inputFile, outputFile)
+}
+
+// TransformLabelCSVFS is the afero-backed implementation of TransformLabelCSV.
+// Passing afero.NewMemMapFs() lets callers (notably tests) exercise the full
+// pipeline without touching disk, and afero.NewCopyOnWriteFs() layered over
+// an OsFs enables dry-run transforms that never mutate the real input.
+func TransformLabelCSVFS(fs afero.Fs, inputFile, outputFile string) error {
+	return TransformLabelCSVFSWithOptions(fs, inputFile, outputFile, TransformOptions{})
+}
+
+// TransformLabelCSVFSWithOptions is TransformLabelCSVFS with explicit control
+// over the input's character encoding. Non-UTF-8 inputs (GBK, Shift-JIS,
+// Latin-1, UTF-16 with a BOM, ...) are transcoded to UTF-8 on the fly; see
+// TransformOptions for how the encoding is chosen.
+func TransformLabelCSVFSWithOptions(fs afero.Fs, inputFile, outputFile string, opts TransformOptions) error {
 	/* Open files --------------------------------------------------------- */
-	in, err := os.Open(inputFile)
+	in, err := fs.Open(inputFile)
 	if err != nil {
 		return fmt.Errorf("open input: %w", err)
 	}
 	defer in.Close()
 
-	out, err := os.Create(outputFile)
+	out, err := fs.Create(outputFile)
 	if err != nil {
 		return fmt.Errorf("create output: %w", err)
 	}
 	defer out.Close()
 
-	const bufSize = 4 << 20 // 4 MiB
+	const bufSize = 4 << 20 // 4 MiB
 	br := bufio.NewReaderSize(in, bufSize)
 	bw := bufio.NewWriterSize(out, bufSize)
 	defer bw.Flush()
 
-	reader := csv.NewReader(br)
-	reader.ReuseRecord = true // avoid per‑row allocations
-
-	writer := csv.NewWriter(bw)
-	defer writer.Flush()
-
-	/* Locate columns ----------------------------------------------------- */
-	header, err := reader.Read()
+	/* Input decompression -------------------------------------------------- */
+	inComp := opts.Compression
+	if inComp == CompressionAuto {
+		peek, _ := br.Peek(16)
+		inComp = detectInputCompression(inputFile, peek)
+	}
+	decompressed, closeDecompressed, err := wrapInputCompression(br, inComp)
 	if err != nil {
-		return fmt.Errorf("read header: %w", err)
+		return fmt.Errorf("input compression: %w", err)
 	}
+	defer closeDecompressed.Close()
 
-	labelIdx := -1
-	trueDurIdx := -1
-	for i, col := range header {
-		colTrim := strings.TrimSpace(col)
-		switch {
-		case strings.EqualFold(colTrim, "label"):
-			labelIdx = i
-		case strings.EqualFold(colTrim, "true_duration_seconds"):
-			trueDurIdx = i
-		}
-	}
-	if labelIdx == -1 {
-		return fmt.Errorf("no column named 'label' found")
-	}
-	if err := writer.Write(header); err != nil {
-		return fmt.Errorf("write header: %w", err)
-	}
+	cbr := bufio.NewReaderSize(decompressed, bufSize)
+	decoded := transform.NewReader(cbr, inputDecoder(cbr, opts))
 
-	/* Stream rows -------------------------------------------------------- */
-	const flushEvery = 100_000
-	rowNum := 1 // header already counted
+	src, err := pipeline.CSVSource(decoded)
+	if err != nil {
+		return err
+	}
 
-	for {
-		row, err := reader.Read()
-		if err == io.EOF {
-			break
+	transformers := defaultColumnOps
+	if opts.Config != nil {
+		if err := opts.Config.Validate(src.Header()); err != nil {
+			return err
 		}
-		rowNum++
+		transformers, err = opts.Config.Transformers()
 		if err != nil {
-			return fmt.Errorf("read row %d: %w", rowNum, err)
+			return err
 		}
+	} else if idx := pipeline.ColumnIndex(src.Header(), "label"); idx == -1 {
+		return fmt.Errorf("no column named 'label' found")
+	}
 
-		// Translate label --------------------------------------------------
-		bin, err := StringToBinary(row[labelIdx])
-		if err != nil {
-			return fmt.Errorf("row %d: %w", rowNum, err)
-		}
-		row[labelIdx] = bin
-
-		// (future‑proofed slot for true_duration_seconds) ------------------
-		if trueDurIdx != -1 { // currently impossible – see early guard
-			v, err := strconv.ParseFloat(row[trueDurIdx], 64)
-			if err != nil {
-				return fmt.Errorf("row %d: invalid true_duration_seconds: %w", rowNum, err)
-			}
-			row[trueDurIdx] = fmt.Sprintf("%f", log1p(v))
-		}
+	/* Output compression ---------------------------------------------------- */
+	outComp := opts.Compression
+	if outComp == CompressionAuto {
+		outComp = compressionFromExt(outputFile)
+	}
+	compOut, err := wrapOutputCompression(bw, outComp)
+	if err != nil {
+		return fmt.Errorf("output compression: %w", err)
+	}
 
-		if err := writer.Write(row); err != nil {
-			return fmt.Errorf("write row %d: %w", rowNum, err)
-		}
-		if rowNum%flushEvery == 0 {
-			writer.Flush()
-			if err := writer.Error(); err != nil {
-				return fmt.Errorf("flush: %w", err)
-			}
-		}
+	runErr := pipeline.RunParallel(src, pipeline.CSVSink(compOut), opts.Workers, transformers...)
+	if closeErr := compOut.Close(); closeErr != nil && runErr == nil {
+		runErr = fmt.Errorf("close compressed output: %w", closeErr)
 	}
-	return writer.Error()
+	return runErr
 }
@@ -5,159 +5,17 @@ import (
 	"fmt"
 	"os"
 	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/tanakachitsamba/labeltransform/config"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/unicode"
 )
 
 /*───────────────────────────────
            TESTS
 ────────────────────────────────*/
 
-// -------- Unit tests for StringToBinary --------
-func TestStringToBinary(t *testing.T) {
-	tests := []struct {
-		in    string
-		want  string
-		isErr bool
-	}{
-		// Normal usage
-		{"true", "1", false},
-		{"false", "0", false},
-		{"TRUE", "1", false},
-		{"False", "0", false},
-		{" True ", "1", false},
-		{"  false  ", "0", false},
-		{"\ntrue\n", "1", false},
-		{"\tfalse\t", "0", false},
-		{"TrUe", "1", false},
-
-		{"positive", "1", false},
-		{"negative", "0", false},
-		{"Yes", "1", false},
-		{"
-		;
-		;#
-		;#
-		#
-		
-		#j;lk'#.
-		
-		
-		
-		
-		
-		
-		
-		
-		
-		
-		
-		
-		
-		
-		
-		
-		
-		
-		
-		
-		
-		
-		
-		
-		:j;l'#
-		
-		#
-		#;
-		
-		#;
-		
-		
-		#4
-		
-		#
-		#
-		4#
-		
-		4
-		
-		
-		#4;
-		#4
-		#4
-		4#
-		
-		#
-		#K:@#
-		;lj#
-		;jl''k#
-		ljk
-		:
-		4
-		4
-		;j'
-		#
-		#
-		4
-		
-		#;';'#
-		
-		#
-		#
-		;#'
-		#
-		#
-		;'#
-		;#'
-		
-		
-		#;'L;
-		#'
-		#
-		#'4:'4;
-		
-		#;L
-		;
-		#
-		#:;#
-		#;'#4;'
-		;#jlk
-		;#jl'k
-
-		j;;4lj
-
-		;j#;lkjj;l#
-		4;l#
-		#
-		4:@~
-		4#
-		4
-		4;)No", "0", false},
-		{" 1 ", "1", false},
-		{" 0 ", "0", false},
-
-		// Edge cases
-		{"", "", true},
-		{"yes", "", true},
-		{"1", "", true},
-		{"0", "", true},
-		{"maybe", "", true},
-		{"tru", "", true},
-		{"falsey", "", true},
-		{"труе", "", true},       // Cyrillic letters
-		{"fałse", "", true},      // Latin-extended char
-		{"t\u200Brue", "", true}, // zero‑width space
-	}
-
-	for _, tt := range tests {
-		got, err := StringToBinary(tt.in)
-		if (err != nil) != tt.isErr {
-			t.Errorf("input %q: expected error %v, got %v", tt.in, tt.isErr, err)
-		}
-		if got != tt.want {
-			t.Errorf("input %q: expected %q, got %q", tt.in, tt.want, got)
-		}
-	}
-}
-
 /* Helper functions for CSV integration tests -------------------------- */
 
 func writeTempCSV(t *testing.T, content [][]string) string {
@@ -323,3 +181,190 @@ func TestTransformLabelCSV(t *testing.T) {
 		})
 	}
 }
+
+/* In-memory filesystem tests for TransformLabelCSVFS ------------------- */
+
+func writeMemCSV(t *testing.T, fs afero.Fs, path string, content [][]string) {
+	f, err := fs.Create(path)
+	if err != nil {
+		t.Fatalf("create %s: %v", path, err)
+	}
+	defer f.Close()
+	w := csv.NewWriter(f)
+	for _, r := range content {
+		if err := w.Write(r); err != nil {
+			t.Fatalf("write row: %v", err)
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		t.Fatalf("flush: %v", err)
+	}
+}
+
+func readMemCSV(t *testing.T, fs afero.Fs, path string) [][]string {
+	f, err := fs.Open(path)
+	if err != nil {
+		t.Fatalf("open %s: %v", path, err)
+	}
+	defer f.Close()
+	r := csv.NewReader(f)
+	all, err := r.ReadAll()
+	if err != nil {
+		t.Fatalf("read csv: %v", err)
+	}
+	return all
+}
+
+func TestTransformLabelCSVFS_MemMapFs(t *testing.T) {
+	fs := afero.NewMemMapFs()
+
+	input := [][]string{
+		{"id", "label", "data"},
+		{"1", "true", "x"},
+		{"2", "false", "y"},
+	}
+	want := [][]string{
+		{"id", "label", "data"},
+		{"1", "1", "x"},
+		{"2", "0", "y"},
+	}
+
+	writeMemCSV(t, fs, "in.csv", input)
+
+	if err := TransformLabelCSVFS(fs, "in.csv", "out.csv"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got := readMemCSV(t, fs, "out.csv")
+	if len(got) != len(want) {
+		t.Fatalf("row count mismatch: want %d, got %d", len(want), len(got))
+	}
+	for i := range got {
+		for j := range got[i] {
+			if got[i][j] != want[i][j] {
+				t.Fatalf("row %d col %d: want %q, got %q", i, j, want[i][j], got[i][j])
+			}
+		}
+	}
+}
+
+/* Character encoding tests ---------------------------------------------- */
+
+func TestTransformLabelCSVFSWithOptions_ExplicitLatin1(t *testing.T) {
+	fs := afero.NewMemMapFs()
+
+	// "café" encoded as Latin-1 (ISO-8859-1): the 'é' is a single byte 0xE9.
+	raw := "id,label,name\r\n1,true,caf\xe9\r\n"
+	if err := afero.WriteFile(fs, "in.csv", []byte(raw), 0o644); err != nil {
+		t.Fatalf("write input: %v", err)
+	}
+
+	opts := TransformOptions{Encoding: charmap.ISO8859_1}
+	if err := TransformLabelCSVFSWithOptions(fs, "in.csv", "out.csv", opts); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got := readMemCSV(t, fs, "out.csv")
+	want := [][]string{
+		{"id", "label", "name"},
+		{"1", "1", "café"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("row count mismatch: want %d, got %d", len(want), len(got))
+	}
+	for i := range got {
+		for j := range got[i] {
+			if got[i][j] != want[i][j] {
+				t.Fatalf("row %d col %d: want %q, got %q", i, j, want[i][j], got[i][j])
+			}
+		}
+	}
+}
+
+func TestTransformLabelCSVFS_AutoDetectsUTF16BOM(t *testing.T) {
+	fs := afero.NewMemMapFs()
+
+	encoded, err := unicode.UTF16(unicode.LittleEndian, unicode.UseBOM).NewEncoder().String("id,label\r\n1,true\r\n")
+	if err != nil {
+		t.Fatalf("encode UTF-16: %v", err)
+	}
+	if err := afero.WriteFile(fs, "in.csv", []byte(encoded), 0o644); err != nil {
+		t.Fatalf("write input: %v", err)
+	}
+
+	// No explicit TransformOptions: BOM sniffing must pick UTF-16LE.
+	if err := TransformLabelCSVFS(fs, "in.csv", "out.csv"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got := readMemCSV(t, fs, "out.csv")
+	want := [][]string{
+		{"id", "label"},
+		{"1", "1"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("row count mismatch: want %d, got %d", len(want), len(got))
+	}
+	for i := range got {
+		for j := range got[i] {
+			if got[i][j] != want[i][j] {
+				t.Fatalf("row %d col %d: want %q, got %q", i, j, want[i][j], got[i][j])
+			}
+		}
+	}
+}
+
+/* Config-driven transforms ----------------------------------------------- */
+
+func TestTransformLabelCSVFSWithOptions_Config(t *testing.T) {
+	fs := afero.NewMemMapFs()
+
+	input := [][]string{
+		{"id", "label", "score", "name"},
+		{"1", "true", "0.6", "ALICE"},
+		{"2", "false", "0.4", "BOB"},
+	}
+	writeMemCSV(t, fs, "in.csv", input)
+
+	cfg := &config.Config{Transforms: []config.ColumnOp{
+		{Column: "label", Kind: "binary"},
+		{Column: "score", Kind: "zscore", Mean: 0.5, StdDev: 0.1},
+		{Column: "name", Kind: "lower"},
+	}}
+	opts := TransformOptions{Config: cfg}
+	if err := TransformLabelCSVFSWithOptions(fs, "in.csv", "out.csv", opts); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got := readMemCSV(t, fs, "out.csv")
+	want := [][]string{
+		{"id", "label", "score", "name"},
+		{"1", "1", "1.000000", "alice"},
+		{"2", "0", "-1.000000", "bob"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("row count mismatch: want %d, got %d", len(want), len(got))
+	}
+	for i := range got {
+		for j := range got[i] {
+			if got[i][j] != want[i][j] {
+				t.Fatalf("row %d col %d: want %q, got %q", i, j, want[i][j], got[i][j])
+			}
+		}
+	}
+}
+
+func TestTransformLabelCSVFSWithOptions_ConfigMissingColumnErrors(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	writeMemCSV(t, fs, "in.csv", [][]string{
+		{"id", "label"},
+		{"1", "true"},
+	})
+
+	cfg := &config.Config{Transforms: []config.ColumnOp{{Column: "missing", Kind: "lower"}}}
+	opts := TransformOptions{Config: cfg}
+	if err := TransformLabelCSVFSWithOptions(fs, "in.csv", "out.csv", opts); err == nil {
+		t.Fatal("expected error for missing configured column")
+	}
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/golang/snappy"
+	"github.com/klauspost/compress/zstd"
+)
+
+// Compression identifies a codec wrapping the raw CSV bytes on disk.
+type Compression int
+
+const (
+	// CompressionAuto detects the codec from the file extension, falling
+	// back to magic-byte sniffing for input files with no recognised
+	// extension. It is the zero value, so it's what TransformOptions uses
+	// unless told otherwise.
+	CompressionAuto Compression = iota
+	CompressionNone
+	CompressionGzip
+	CompressionSnappy
+	CompressionZstd
+)
+
+// gzipMagic, zstdMagic and snappyStreamMagic are the leading bytes that
+// identify each codec when the filename itself gives no hint.
+var (
+	gzipMagic         = []byte{0x1f, 0x8b}
+	zstdMagic         = []byte{0x28, 0xb5, 0x2f, 0xfd}
+	snappyStreamMagic = []byte{0xff, 0x06, 0x00, 0x00, 0x73, 0x4e, 0x61, 0x50, 0x70, 0x59}
+)
+
+// compressionFromExt maps a .csv.gz / .csv.sz / .csv.zst style suffix to its
+// Compression, or CompressionNone if the name doesn't end in one.
+func compressionFromExt(name string) Compression {
+	switch {
+	case strings.HasSuffix(name, ".gz"):
+		return CompressionGzip
+	case strings.HasSuffix(name, ".sz"):
+		return CompressionSnappy
+	case strings.HasSuffix(name, ".zst"):
+		return CompressionZstd
+	default:
+		return CompressionNone
+	}
+}
+
+// detectInputCompression resolves the codec for inputFile: its extension if
+// recognised, otherwise the leading bytes peeked from the file.
+func detectInputCompression(inputFile string, peek []byte) Compression {
+	if c := compressionFromExt(inputFile); c != CompressionNone {
+		return c
+	}
+	switch {
+	case bytes.HasPrefix(peek, gzipMagic):
+		return CompressionGzip
+	case bytes.HasPrefix(peek, zstdMagic):
+		return CompressionZstd
+	case bytes.HasPrefix(peek, snappyStreamMagic):
+		return CompressionSnappy
+	default:
+		return CompressionNone
+	}
+}
+
+// wrapInputCompression returns r decompressed according to c, and a closer
+// that must be called once the caller is done reading.
+func wrapInputCompression(r io.Reader, c Compression) (io.Reader, io.Closer, error) {
+	switch c {
+	case CompressionGzip:
+		gz, err := gzip.NewReader(r)
+		if err != nil {
+			return nil, nil, fmt.Errorf("gzip reader: %w", err)
+		}
+		return gz, gz, nil
+	case CompressionSnappy:
+		return snappy.NewReader(r), io.NopCloser(nil), nil
+	case CompressionZstd:
+		zr, err := zstd.NewReader(r)
+		if err != nil {
+			return nil, nil, fmt.Errorf("zstd reader: %w", err)
+		}
+		rc := zr.IOReadCloser()
+		return rc, rc, nil
+	default:
+		return r, io.NopCloser(nil), nil
+	}
+}
+
+// wrapOutputCompression returns w compressing according to c, and the
+// writer whose Close flushes the codec's trailer. The caller must Close it
+// before closing/flushing anything that wraps w.
+func wrapOutputCompression(w io.Writer, c Compression) (io.WriteCloser, error) {
+	switch c {
+	case CompressionGzip:
+		return gzip.NewWriter(w), nil
+	case CompressionSnappy:
+		return snappy.NewBufferedWriter(w), nil
+	case CompressionZstd:
+		return zstd.NewWriter(w)
+	default:
+		return nopWriteCloser{w}, nil
+	}
+}
+
+type nopWriteCloser struct{ io.Writer }
+
+func (nopWriteCloser) Close() error { return nil }
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/csv"
+	"io"
+	"testing"
+
+	"github.com/golang/snappy"
+	"github.com/klauspost/compress/zstd"
+	"github.com/spf13/afero"
+)
+
+func readCSVBytes(t *testing.T, data []byte) [][]string {
+	all, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+	if err != nil {
+		t.Fatalf("read csv: %v", err)
+	}
+	return all
+}
+
+func TestTransformLabelCSVFS_CompressedRoundTrip(t *testing.T) {
+	plain := "id,label\n1,true\n2,false\n"
+	want := [][]string{
+		{"id", "label"},
+		{"1", "1"},
+		{"2", "0"},
+	}
+
+	cases := []struct {
+		name   string
+		inExt  string
+		outExt string
+		encode func(t *testing.T, plain string) []byte
+	}{
+		{
+			name:   "gzip in, gzip out",
+			inExt:  ".csv.gz",
+			outExt: ".csv.gz",
+			encode: func(t *testing.T, plain string) []byte {
+				var buf bytes.Buffer
+				gw := gzip.NewWriter(&buf)
+				if _, err := gw.Write([]byte(plain)); err != nil {
+					t.Fatalf("gzip write: %v", err)
+				}
+				if err := gw.Close(); err != nil {
+					t.Fatalf("gzip close: %v", err)
+				}
+				return buf.Bytes()
+			},
+		},
+		{
+			name:   "snappy in, plain out",
+			inExt:  ".csv.sz",
+			outExt: ".csv",
+			encode: func(t *testing.T, plain string) []byte {
+				var buf bytes.Buffer
+				sw := snappy.NewBufferedWriter(&buf)
+				if _, err := sw.Write([]byte(plain)); err != nil {
+					t.Fatalf("snappy write: %v", err)
+				}
+				if err := sw.Close(); err != nil {
+					t.Fatalf("snappy close: %v", err)
+				}
+				return buf.Bytes()
+			},
+		},
+		{
+			name:   "zstd in, zstd out",
+			inExt:  ".csv.zst",
+			outExt: ".csv.zst",
+			encode: func(t *testing.T, plain string) []byte {
+				var buf bytes.Buffer
+				zw, err := zstd.NewWriter(&buf)
+				if err != nil {
+					t.Fatalf("zstd writer: %v", err)
+				}
+				if _, err := zw.Write([]byte(plain)); err != nil {
+					t.Fatalf("zstd write: %v", err)
+				}
+				if err := zw.Close(); err != nil {
+					t.Fatalf("zstd close: %v", err)
+				}
+				return buf.Bytes()
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			fs := afero.NewMemMapFs()
+			inPath := "in" + tc.inExt
+			outPath := "out" + tc.outExt
+
+			if err := afero.WriteFile(fs, inPath, tc.encode(t, plain), 0o644); err != nil {
+				t.Fatalf("write input: %v", err)
+			}
+
+			if err := TransformLabelCSVFS(fs, inPath, outPath); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			outBytes, err := afero.ReadFile(fs, outPath)
+			if err != nil {
+				t.Fatalf("read output: %v", err)
+			}
+
+			var plainOut []byte
+			switch compressionFromExt(outPath) {
+			case CompressionGzip:
+				gr, err := gzip.NewReader(bytes.NewReader(outBytes))
+				if err != nil {
+					t.Fatalf("gzip reader: %v", err)
+				}
+				plainOut, err = io.ReadAll(gr)
+				if err != nil {
+					t.Fatalf("gzip read: %v", err)
+				}
+			case CompressionZstd:
+				zr, err := zstd.NewReader(bytes.NewReader(outBytes))
+				if err != nil {
+					t.Fatalf("zstd reader: %v", err)
+				}
+				defer zr.Close()
+				plainOut, err = io.ReadAll(zr)
+				if err != nil {
+					t.Fatalf("zstd read: %v", err)
+				}
+			default:
+				plainOut = outBytes
+			}
+
+			got := readCSVBytes(t, plainOut)
+			if len(got) != len(want) {
+				t.Fatalf("row count mismatch: want %d, got %d (%q)", len(want), len(got), plainOut)
+			}
+			for i := range got {
+				for j := range got[i] {
+					if got[i][j] != want[i][j] {
+						t.Fatalf("row %d col %d: want %q, got %q", i, j, want[i][j], got[i][j])
+					}
+				}
+			}
+		})
+	}
+}
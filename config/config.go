@@ -0,0 +1,92 @@
+// Package config loads the declarative list of per-column transforms that
+// drives a pipeline run, in place of a fixed set of column names baked into
+// the program.
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/tanakachitsamba/labeltransform/pipeline"
+	"gopkg.in/yaml.v3"
+)
+
+// ColumnOp declares one column transform: {column: "label", kind: "binary"},
+// {column: "duration_s", kind: "log1p"},
+// {column: "score", kind: "zscore", mean: 0.5, stddev: 0.1}, or
+// {column: "name", kind: "lower"}.
+type ColumnOp struct {
+	Column string  `json:"column" yaml:"column"`
+	Kind   string  `json:"kind" yaml:"kind"`
+	Mean   float64 `json:"mean,omitempty" yaml:"mean,omitempty"`
+	StdDev float64 `json:"stddev,omitempty" yaml:"stddev,omitempty"`
+}
+
+// Config is the parsed form of a transform config file: an ordered list of
+// column operations applied to every row.
+type Config struct {
+	Transforms []ColumnOp `json:"transforms" yaml:"transforms"`
+}
+
+// Parse reads a Config from data. format selects the syntax ("yaml" or
+// "json"); anything else is rejected rather than guessed.
+func Parse(data []byte, format string) (*Config, error) {
+	var cfg Config
+	switch format {
+	case "yaml":
+		if err := yaml.Unmarshal(data, &cfg); err != nil {
+			return nil, fmt.Errorf("parse yaml config: %w", err)
+		}
+	case "json":
+		if err := json.Unmarshal(data, &cfg); err != nil {
+			return nil, fmt.Errorf("parse json config: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported config format %q", format)
+	}
+	return &cfg, nil
+}
+
+// FormatFromExt maps a config file's extension to the format Parse expects,
+// defaulting to "yaml" for anything that isn't .json.
+func FormatFromExt(path string) string {
+	if strings.HasSuffix(path, ".json") {
+		return "json"
+	}
+	return "yaml"
+}
+
+// Validate checks that every configured column is present in header,
+// returning a single error describing the first one that isn't rather than
+// silently skipping it.
+func (c *Config) Validate(header []string) error {
+	for _, op := range c.Transforms {
+		if pipeline.ColumnIndex(header, op.Column) == -1 {
+			return fmt.Errorf("config: no column named %q found", op.Column)
+		}
+	}
+	return nil
+}
+
+// Transformers builds the pipeline.Transformer chain described by c, in the
+// order the ColumnOps were declared. An unrecognised Kind is a startup
+// error.
+func (c *Config) Transformers() ([]pipeline.Transformer, error) {
+	transformers := make([]pipeline.Transformer, 0, len(c.Transforms))
+	for _, op := range c.Transforms {
+		switch op.Kind {
+		case "binary":
+			transformers = append(transformers, pipeline.LabelBinarizer{Column: op.Column})
+		case "log1p":
+			transformers = append(transformers, pipeline.Log1pColumn{Column: op.Column})
+		case "zscore":
+			transformers = append(transformers, pipeline.ZScore{Column: op.Column, Mean: op.Mean, StdDev: op.StdDev})
+		case "lower":
+			transformers = append(transformers, pipeline.Lower{Column: op.Column})
+		default:
+			return nil, fmt.Errorf("column %q: unknown transform kind %q", op.Column, op.Kind)
+		}
+	}
+	return transformers, nil
+}
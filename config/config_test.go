@@ -0,0 +1,89 @@
+package config
+
+import "testing"
+
+func TestParse_YAML(t *testing.T) {
+	data := []byte(`
+transforms:
+  - column: label
+    kind: binary
+  - column: score
+    kind: zscore
+    mean: 0.5
+    stddev: 0.1
+`)
+	cfg, err := Parse(data, "yaml")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if len(cfg.Transforms) != 2 {
+		t.Fatalf("got %d transforms, want 2", len(cfg.Transforms))
+	}
+	if cfg.Transforms[1].Mean != 0.5 || cfg.Transforms[1].StdDev != 0.1 {
+		t.Errorf("zscore params not parsed: %+v", cfg.Transforms[1])
+	}
+}
+
+func TestParse_JSON(t *testing.T) {
+	data := []byte(`{"transforms":[{"column":"name","kind":"lower"}]}`)
+	cfg, err := Parse(data, "json")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if len(cfg.Transforms) != 1 || cfg.Transforms[0].Kind != "lower" {
+		t.Errorf("got %+v", cfg.Transforms)
+	}
+}
+
+func TestParse_UnsupportedFormat(t *testing.T) {
+	if _, err := Parse([]byte("{}"), "toml"); err == nil {
+		t.Fatal("expected error for unsupported format")
+	}
+}
+
+func TestFormatFromExt(t *testing.T) {
+	cases := map[string]string{
+		"config.json": "json",
+		"config.yaml": "yaml",
+		"config.yml":  "yaml",
+		"config":      "yaml",
+	}
+	for path, want := range cases {
+		if got := FormatFromExt(path); got != want {
+			t.Errorf("FormatFromExt(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
+
+func TestConfig_Validate(t *testing.T) {
+	cfg := &Config{Transforms: []ColumnOp{{Column: "label", Kind: "binary"}}}
+	if err := cfg.Validate([]string{"id", "label"}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := cfg.Validate([]string{"id"}); err == nil {
+		t.Error("expected error for missing column")
+	}
+}
+
+func TestConfig_Transformers(t *testing.T) {
+	cfg := &Config{Transforms: []ColumnOp{
+		{Column: "label", Kind: "binary"},
+		{Column: "duration_s", Kind: "log1p"},
+		{Column: "score", Kind: "zscore", Mean: 0.5, StdDev: 0.1},
+		{Column: "name", Kind: "lower"},
+	}}
+	transformers, err := cfg.Transformers()
+	if err != nil {
+		t.Fatalf("Transformers: %v", err)
+	}
+	if len(transformers) != 4 {
+		t.Fatalf("got %d transformers, want 4", len(transformers))
+	}
+}
+
+func TestConfig_Transformers_UnknownKind(t *testing.T) {
+	cfg := &Config{Transforms: []ColumnOp{{Column: "label", Kind: "frobnicate"}}}
+	if _, err := cfg.Transformers(); err == nil {
+		t.Fatal("expected error for unknown kind")
+	}
+}
@@ -1,14 +1,36 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"log"
+	"os"
+
+	"github.com/spf13/afero"
+	"github.com/tanakachitsamba/labeltransform/config"
 )
 
 func main() {
-	input := "ALL_DATASET_UNCLEAN_11_08.csv"
-	output := "label_transformed_output.csv"
-	if err := TransformLabelCSV(input, output); err != nil {
+	input := flag.String("input", "ALL_DATASET_UNCLEAN_11_08.csv", "input CSV file (optionally compressed, optionally non-UTF-8)")
+	output := flag.String("output", "label_transformed_output.csv", "output CSV file")
+	configPath := flag.String("config", "", "path to a YAML/JSON column-transform config; defaults to the built-in label/true_duration_seconds transforms")
+	flag.Parse()
+
+	opts := TransformOptions{}
+	if *configPath != "" {
+		data, err := os.ReadFile(*configPath)
+		if err != nil {
+			log.Fatalf("read config: %v", err)
+		}
+		cfg, err := config.Parse(data, config.FormatFromExt(*configPath))
+		if err != nil {
+			log.Fatalf("parse config: %v", err)
+		}
+		opts.Config = cfg
+	}
+
+	fs := afero.NewOsFs()
+	if err := TransformLabelCSVFSWithOptions(fs, *input, *output, opts); err != nil {
 		log.Fatalf("Transformation failed: %v", err)
 	}
 	fmt.Println("Label Transformation succeeded!")
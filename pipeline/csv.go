@@ -0,0 +1,92 @@
+package pipeline
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+)
+
+// csvSource is a Source backed by an encoding/csv.Reader.
+type csvSource struct {
+	reader *csv.Reader
+	header []string
+}
+
+// CSVSource reads the CSV header eagerly from r (so callers can validate
+// required columns before starting the row stream) and returns a Source
+// that streams the remaining rows.
+func CSVSource(r io.Reader) (Source, error) {
+	reader := csv.NewReader(r)
+	reader.ReuseRecord = true // avoid per-row allocations
+
+	rawHeader, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("read header: %w", err)
+	}
+	// ReuseRecord means rawHeader is overwritten on the next Read; clone it
+	// since it must outlive every subsequent row.
+	header := make([]string, len(rawHeader))
+	copy(header, rawHeader)
+	return &csvSource{reader: reader, header: header}, nil
+}
+
+func (s *csvSource) Header() []string { return s.header }
+
+func (s *csvSource) Rows() <-chan Row {
+	out := make(chan Row)
+	go func() {
+		defer close(out)
+		num := 1
+		for {
+			fields, err := s.reader.Read()
+			if err == io.EOF {
+				return
+			}
+			num++
+			if err != nil {
+				out <- Row{Header: s.header, Num: num, Err: fmt.Errorf("read row %d: %w", num, err)}
+				return
+			}
+			// ReuseRecord means fields is overwritten on the next Read;
+			// clone it so it can safely cross the channel.
+			row := make([]string, len(fields))
+			copy(row, fields)
+			out <- Row{Header: s.header, Fields: row, Num: num}
+		}
+	}()
+	return out
+}
+
+// csvSink is a Sink that writes Rows out via an encoding/csv.Writer, writing
+// the shared header once before the first row.
+type csvSink struct {
+	w io.Writer
+}
+
+// CSVSink writes the rows it consumes as CSV to w, writing the header row
+// (Num 0) before any data rows, even if the stream carries zero data rows or
+// errors out on its first one.
+func CSVSink(w io.Writer) Sink {
+	return &csvSink{w: w}
+}
+
+func (s *csvSink) Consume(rows <-chan Row) error {
+	writer := csv.NewWriter(s.w)
+	defer writer.Flush()
+
+	for row := range rows {
+		if row.Num == 0 {
+			if err := writer.Write(row.Header); err != nil {
+				return fmt.Errorf("write header: %w", err)
+			}
+			continue
+		}
+		if row.Err != nil {
+			return row.Err
+		}
+		if err := writer.Write(row.Fields); err != nil {
+			return fmt.Errorf("write row %d: %w", row.Num, err)
+		}
+	}
+	return writer.Error()
+}
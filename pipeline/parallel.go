@@ -0,0 +1,143 @@
+package pipeline
+
+import (
+	"context"
+	"runtime"
+	"sync"
+)
+
+// job pairs a Row with the channel its transformed result must be delivered
+// on, so results can be handed back to the sink in the same order the rows
+// were read regardless of which worker finishes first.
+type job struct {
+	row Row
+	out chan Row
+}
+
+// RunParallel is Run with the row-by-row Transformer application spread
+// across workers goroutines. Row order is preserved: each dispatched Row
+// gets its own single-slot result channel, and those channels are drained
+// strictly in dispatch order, so a fast worker finishing a later row simply
+// waits in its channel until the earlier rows have been written.
+//
+// workers <= 0 defaults to runtime.GOMAXPROCS(0). The first error - whether
+// a read error from src or a Transformer error - cancels outstanding work
+// and is returned with whatever row number the failing Transformer reported.
+// As in Run, the header reaches sink as its own Row (Num 0, no Fields)
+// before any data row.
+func RunParallel(src Source, sink Sink, workers int, transformers ...Transformer) error {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	jobs := make(chan job, workers)
+	order := make(chan chan Row, workers)
+
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				j.out <- applyAll(ctx, j.row, transformers)
+			}
+		}()
+	}
+
+	go dispatch(ctx, cancel, src, jobs, order)
+
+	out := make(chan Row)
+	go func() {
+		defer close(out)
+		select {
+		case out <- Row{Header: src.Header()}:
+		case <-ctx.Done():
+			return
+		}
+		for ch := range order {
+			row := <-ch
+			select {
+			case out <- row:
+			case <-ctx.Done():
+				return
+			}
+			if row.Err != nil {
+				cancel() // stop any worker still processing a later row
+				return
+			}
+		}
+	}()
+
+	err := sink.Consume(out)
+	cancel()
+	wg.Wait()
+	return err
+}
+
+// applyAll runs a Row through every transformer in order, short-circuiting
+// on the first error and skipping entirely if ctx was already cancelled or
+// the Row already carries an upstream error (e.g. a CSV read failure).
+func applyAll(ctx context.Context, row Row, transformers []Transformer) Row {
+	if row.Err != nil {
+		return row
+	}
+	select {
+	case <-ctx.Done():
+		row.Err = ctx.Err()
+		return row
+	default:
+	}
+	for _, tr := range transformers {
+		next, err := tr.Apply(row)
+		if err != nil {
+			row.Err = err
+			return row
+		}
+		row = next
+	}
+	return row
+}
+
+// dispatch reads rows from src in order and hands each to the worker pool,
+// publishing that row's single-slot result channel on order so the fan-in
+// goroutine in RunParallel can drain results in the same sequence.
+func dispatch(ctx context.Context, cancel context.CancelFunc, src Source, jobs chan<- job, order chan<- chan Row) {
+	defer close(jobs)
+	defer close(order)
+	rows := src.Rows()
+	for row := range rows {
+		ch := make(chan Row, 1)
+		select {
+		case jobs <- job{row: row, out: ch}:
+		case <-ctx.Done():
+			ch <- Row{Err: ctx.Err()}
+			order <- ch
+			drain(rows)
+			return
+		}
+		select {
+		case order <- ch:
+		case <-ctx.Done():
+			drain(rows)
+			return
+		}
+		if row.Err != nil {
+			cancel()
+			drain(rows)
+			return
+		}
+	}
+}
+
+// drain discards the remainder of rows in the background so a producer
+// blocked sending to it (e.g. CSVSource's goroutine) is never stranded once
+// dispatch stops consuming early because of a cancellation or error.
+func drain(rows <-chan Row) {
+	go func() {
+		for range rows {
+		}
+	}()
+}
@@ -0,0 +1,173 @@
+package pipeline
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// slowDouble doubles a numeric column, taking longer on smaller values so
+// that, without the reorder buffer, faster-finishing later rows would race
+// ahead of earlier ones.
+type slowDouble struct{ Column string }
+
+func (d slowDouble) Apply(row Row) (Row, error) {
+	idx := ColumnIndex(row.Header, d.Column)
+	if idx == -1 {
+		return row, fmt.Errorf("no column named %q found", d.Column)
+	}
+	v, err := strconv.Atoi(row.Fields[idx])
+	if err != nil {
+		return row, err
+	}
+	row.Fields[idx] = strconv.Itoa(v * 2)
+	return row, nil
+}
+
+func TestRunParallel_PreservesOrder(t *testing.T) {
+	var sb strings.Builder
+	sb.WriteString("id,n\n")
+	for i := 1; i <= 200; i++ {
+		fmt.Fprintf(&sb, "%d,%d\n", i, i)
+	}
+
+	src, err := CSVSource(strings.NewReader(sb.String()))
+	if err != nil {
+		t.Fatalf("CSVSource: %v", err)
+	}
+
+	var out strings.Builder
+	if err := RunParallel(src, CSVSink(&out), 8, slowDouble{Column: "n"}); err != nil {
+		t.Fatalf("RunParallel: %v", err)
+	}
+
+	var want strings.Builder
+	want.WriteString("id,n\n")
+	for i := 1; i <= 200; i++ {
+		fmt.Fprintf(&want, "%d,%d\n", i, i*2)
+	}
+	if out.String() != want.String() {
+		t.Fatalf("output rows out of order or incorrect")
+	}
+}
+
+func TestRunParallel_DefaultWorkerCount(t *testing.T) {
+	input := "id,label\n1,true\n2,false\n"
+	src, err := CSVSource(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("CSVSource: %v", err)
+	}
+
+	var out strings.Builder
+	if err := RunParallel(src, CSVSink(&out), 0, LabelBinarizer{Column: "label"}); err != nil {
+		t.Fatalf("RunParallel: %v", err)
+	}
+
+	want := "id,label\n1,1\n2,0\n"
+	if out.String() != want {
+		t.Errorf("got %q, want %q", out.String(), want)
+	}
+}
+
+func TestRunParallel_HeaderOnlyInputStillWritesHeader(t *testing.T) {
+	input := "id,label\n"
+	src, err := CSVSource(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("CSVSource: %v", err)
+	}
+
+	var out strings.Builder
+	if err := RunParallel(src, CSVSink(&out), 4, LabelBinarizer{Column: "label"}); err != nil {
+		t.Fatalf("RunParallel: %v", err)
+	}
+
+	want := "id,label\n"
+	if out.String() != want {
+		t.Errorf("got %q, want %q", out.String(), want)
+	}
+}
+
+func TestRunParallel_ErrorOnFirstRowStillWritesHeader(t *testing.T) {
+	input := "id,label\n1,maybe\n"
+	src, err := CSVSource(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("CSVSource: %v", err)
+	}
+
+	var out strings.Builder
+	err = RunParallel(src, CSVSink(&out), 4, LabelBinarizer{Column: "label"})
+	if err == nil {
+		t.Fatalf("expected an error, got nil")
+	}
+
+	want := "id,label\n"
+	if out.String() != want {
+		t.Errorf("got %q, want %q", out.String(), want)
+	}
+}
+
+func TestRunParallel_ErrorSurfacesWithRowNumber(t *testing.T) {
+	input := "id,label\n1,true\n2,maybe\n3,false\n"
+	src, err := CSVSource(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("CSVSource: %v", err)
+	}
+
+	var out strings.Builder
+	err = RunParallel(src, CSVSink(&out), 4, LabelBinarizer{Column: "label"})
+	if err == nil {
+		t.Fatalf("expected an error, got nil")
+	}
+	if !strings.Contains(err.Error(), "row 3") {
+		t.Errorf("expected error to reference row 3, got: %v", err)
+	}
+}
+
+// earlyErrorSink errors out after consuming n rows without draining the
+// rest of the channel, mimicking a real csv.Writer failure (disk full,
+// broken pipe) partway through a run.
+type earlyErrorSink struct{ n int }
+
+func (s earlyErrorSink) Consume(rows <-chan Row) error {
+	count := 0
+	for range rows {
+		count++
+		if count == s.n {
+			return errors.New("sink failed")
+		}
+	}
+	return nil
+}
+
+func TestRunParallel_SinkErrorDoesNotLeakGoroutines(t *testing.T) {
+	var sb strings.Builder
+	sb.WriteString("id,n\n")
+	for i := 1; i <= 2000; i++ {
+		fmt.Fprintf(&sb, "%d,%d\n", i, i)
+	}
+
+	before := runtime.NumGoroutine()
+
+	src, err := CSVSource(strings.NewReader(sb.String()))
+	if err != nil {
+		t.Fatalf("CSVSource: %v", err)
+	}
+	if err := RunParallel(src, earlyErrorSink{n: 2}, 4, slowDouble{Column: "n"}); err == nil {
+		t.Fatalf("expected sink error, got nil")
+	}
+
+	// Give any leaked goroutines a chance to actually finish, then make
+	// sure none are left stuck blocked on a send that nobody will ever
+	// receive.
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > before && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if got := runtime.NumGoroutine(); got > before {
+		t.Errorf("goroutine leak: started with %d, left with %d", before, got)
+	}
+}
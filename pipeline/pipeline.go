@@ -0,0 +1,82 @@
+// Package pipeline implements a small Optimus-style Source/Sink/Transformer
+// pipeline for streaming row-oriented transforms over CSV data.
+package pipeline
+
+import "strings"
+
+// Row is a single CSV record flowing through a pipeline. Header is shared
+// across all Rows from the same Source so Transformers can resolve a
+// column by name. Num is the 1-based data-row number (the header is row 0),
+// used for error messages. A Row with a non-nil Err is the last Row a
+// Source or the pipeline runner will ever produce.
+type Row struct {
+	Header []string
+	Fields []string
+	Num    int
+	Err    error
+}
+
+// Source produces a stream of Rows, closing the channel once exhausted (or
+// once it has emitted a Row carrying a terminal Err).
+type Source interface {
+	// Header returns the column names shared by every Row this Source
+	// produces, so callers can validate required columns up front.
+	Header() []string
+	Rows() <-chan Row
+}
+
+// Sink consumes a stream of Rows, returning the first error encountered
+// (including one forwarded via Row.Err).
+type Sink interface {
+	Consume(<-chan Row) error
+}
+
+// Transformer maps one Row to another. Returning an error aborts the
+// pipeline after the offending Row.
+type Transformer interface {
+	Apply(Row) (Row, error)
+}
+
+// Run streams src through each transformer in order and into sink,
+// returning the first error encountered. The header reaches sink as its own
+// Row (Num 0, no Fields) before any data row, even if src produces zero data
+// rows or the first one errors.
+func Run(src Source, sink Sink, transformers ...Transformer) error {
+	out := make(chan Row)
+	go func() {
+		defer close(out)
+		out <- Row{Header: src.Header()}
+		for row := range src.Rows() {
+			if row.Err != nil {
+				out <- row
+				return
+			}
+			for _, tr := range transformers {
+				next, err := tr.Apply(row)
+				if err != nil {
+					row.Err = err
+					break
+				}
+				row = next
+			}
+			out <- row
+			if row.Err != nil {
+				return
+			}
+		}
+	}()
+	return sink.Consume(out)
+}
+
+// ColumnIndex returns the index of name in header (case-insensitively,
+// ignoring surrounding whitespace), or -1 if it isn't present. Transformers
+// use it to resolve their configured column, and callers can use it to
+// validate required columns before a pipeline starts running.
+func ColumnIndex(header []string, name string) int {
+	for i, col := range header {
+		if strings.EqualFold(strings.TrimSpace(col), name) {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,109 @@
+package pipeline
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRun_LabelBinarizerAndLog1pColumn(t *testing.T) {
+	input := "id,label,true_duration_seconds\n1,true,0\n2,false,1.5\n"
+
+	src, err := CSVSource(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("CSVSource: %v", err)
+	}
+
+	var out strings.Builder
+	err = Run(src, CSVSink(&out),
+		LabelBinarizer{Column: "label"},
+		Log1pColumn{Column: "true_duration_seconds"},
+	)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	want := "id,label,true_duration_seconds\n1,1,0.000000\n2,0,0.916291\n"
+	if out.String() != want {
+		t.Errorf("got:\n%s\nwant:\n%s", out.String(), want)
+	}
+}
+
+func TestRun_MissingRequiredColumnErrors(t *testing.T) {
+	input := "id,other\n1,x\n"
+
+	src, err := CSVSource(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("CSVSource: %v", err)
+	}
+	if idx := ColumnIndex(src.Header(), "label"); idx != -1 {
+		t.Fatalf("expected label column to be absent, found at %d", idx)
+	}
+
+	var out strings.Builder
+	err = Run(src, CSVSink(&out), LabelBinarizer{Column: "label"})
+	if err == nil {
+		t.Fatalf("expected an error, got nil")
+	}
+}
+
+func TestRun_HeaderOnlyInputStillWritesHeader(t *testing.T) {
+	input := "id,label\n"
+
+	src, err := CSVSource(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("CSVSource: %v", err)
+	}
+
+	var out strings.Builder
+	if err := Run(src, CSVSink(&out), LabelBinarizer{Column: "label"}); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	want := "id,label\n"
+	if out.String() != want {
+		t.Errorf("got %q, want %q", out.String(), want)
+	}
+}
+
+func TestRun_ErrorOnFirstRowStillWritesHeader(t *testing.T) {
+	input := "id,label\n1,maybe\n"
+
+	src, err := CSVSource(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("CSVSource: %v", err)
+	}
+
+	var out strings.Builder
+	err = Run(src, CSVSink(&out), LabelBinarizer{Column: "label"})
+	if err == nil {
+		t.Fatalf("expected an error, got nil")
+	}
+
+	want := "id,label\n"
+	if out.String() != want {
+		t.Errorf("got %q, want %q", out.String(), want)
+	}
+}
+
+func TestLog1pColumn_SkipsMissingColumn(t *testing.T) {
+	input := "id,label\n1,true\n"
+
+	src, err := CSVSource(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("CSVSource: %v", err)
+	}
+
+	var out strings.Builder
+	err = Run(src, CSVSink(&out),
+		LabelBinarizer{Column: "label"},
+		Log1pColumn{Column: "true_duration_seconds"},
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := "id,label\n1,1\n"
+	if out.String() != want {
+		t.Errorf("got %q, want %q", out.String(), want)
+	}
+}
@@ -0,0 +1,119 @@
+package pipeline
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// LabelBinarizer rewrites Column from a human-readable boolean token
+// ("true"/"false", "yes"/"no", "positive"/"negative", "1"/"0", any casing
+// or surrounding whitespace) into "1"/"0". Column must be present in the
+// Row's header; its absence is an error, not a skip.
+type LabelBinarizer struct {
+	Column string
+}
+
+// Apply implements Transformer.
+func (b LabelBinarizer) Apply(row Row) (Row, error) {
+	idx := ColumnIndex(row.Header, b.Column)
+	if idx == -1 {
+		return row, fmt.Errorf("no column named %q found", b.Column)
+	}
+	bin, err := stringToBinary(row.Fields[idx])
+	if err != nil {
+		return row, fmt.Errorf("row %d: %w", row.Num, err)
+	}
+	row.Fields[idx] = bin
+	return row, nil
+}
+
+// Log1pColumn replaces Column with math.Log1p of its numeric value. Rows
+// whose header lacks Column pass through unchanged, since this transformer
+// is meant to be registered unconditionally for datasets that may or may
+// not carry the column.
+type Log1pColumn struct {
+	Column string
+}
+
+// Apply implements Transformer.
+func (l Log1pColumn) Apply(row Row) (Row, error) {
+	idx := ColumnIndex(row.Header, l.Column)
+	if idx == -1 {
+		return row, nil
+	}
+	v, err := strconv.ParseFloat(row.Fields[idx], 64)
+	if err != nil {
+		return row, fmt.Errorf("row %d: invalid %s: %w", row.Num, l.Column, err)
+	}
+	row.Fields[idx] = fmt.Sprintf("%f", log1p(v))
+	return row, nil
+}
+
+// ZScore replaces Column with (value-Mean)/StdDev. Column must be present
+// in the Row's header.
+type ZScore struct {
+	Column string
+	Mean   float64
+	StdDev float64
+}
+
+// Apply implements Transformer.
+func (z ZScore) Apply(row Row) (Row, error) {
+	idx := ColumnIndex(row.Header, z.Column)
+	if idx == -1 {
+		return row, fmt.Errorf("no column named %q found", z.Column)
+	}
+	v, err := strconv.ParseFloat(row.Fields[idx], 64)
+	if err != nil {
+		return row, fmt.Errorf("row %d: invalid %s: %w", row.Num, z.Column, err)
+	}
+	row.Fields[idx] = fmt.Sprintf("%f", (v-z.Mean)/z.StdDev)
+	return row, nil
+}
+
+// Lower lowercases Column's value. Column must be present in the Row's
+// header.
+type Lower struct {
+	Column string
+}
+
+// Apply implements Transformer.
+func (l Lower) Apply(row Row) (Row, error) {
+	idx := ColumnIndex(row.Header, l.Column)
+	if idx == -1 {
+		return row, fmt.Errorf("no column named %q found", l.Column)
+	}
+	row.Fields[idx] = strings.ToLower(row.Fields[idx])
+	return row, nil
+}
+
+// stringToBinary is LabelBinarizer's token mapping.
+func stringToBinary(val string) (string, error) {
+	s := strings.TrimSpace(strings.ToLower(val))
+
+	binMap := map[string]string{
+		"true":     "1",
+		"positive": "1",
+		"yes":      "1",
+		"1":        "1",
+		"false":    "0",
+		"negative": "0",
+		"no":       "0",
+		"0":        "0",
+	}
+	if b, ok := binMap[s]; ok {
+		return b, nil
+	}
+	return "", fmt.Errorf("unexpected label value: %q", val)
+}
+
+// log1p mirrors the main package's helper: math.Log1p returns NaN for
+// negative numbers, so those are floored to 0 instead.
+func log1p(x float64) float64 {
+	if x < 0 {
+		return 0
+	}
+	return math.Log1p(x)
+}
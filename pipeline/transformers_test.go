@@ -0,0 +1,48 @@
+package pipeline
+
+import "testing"
+
+func TestStringToBinary(t *testing.T) {
+	tests := []struct {
+		in    string
+		want  string
+		isErr bool
+	}{
+		// Normal usage
+		{"true", "1", false},
+		{"false", "0", false},
+		{"TRUE", "1", false},
+		{"False", "0", false},
+		{" True ", "1", false},
+		{"  false  ", "0", false},
+		{"\ntrue\n", "1", false},
+		{"\tfalse\t", "0", false},
+		{"TrUe", "1", false},
+
+		{"positive", "1", false},
+		{"negative", "0", false},
+		{"Yes", "1", false},
+		{"n\x00o", "", true}, // embedded NUL / control bytes
+		{" 1 ", "1", false},
+		{" 0 ", "0", false},
+
+		// Edge cases
+		{"", "", true},
+		{"maybe", "", true},
+		{"tru", "", true},
+		{"falsey", "", true},
+		{"труе", "", true},       // Cyrillic letters
+		{"fałse", "", true},      // Latin-extended char
+		{"t​rue", "", true}, // zero‑width space
+	}
+
+	for _, tt := range tests {
+		got, err := stringToBinary(tt.in)
+		if (err != nil) != tt.isErr {
+			t.Errorf("input %q: expected error %v, got %v", tt.in, tt.isErr, err)
+		}
+		if got != tt.want {
+			t.Errorf("input %q: expected %q, got %q", tt.in, tt.want, got)
+		}
+	}
+}